@@ -0,0 +1,72 @@
+package mediadevices
+
+import (
+	"github.com/pion/mediadevices/pkg/prop"
+)
+
+// MediaTrackConstraintSet holds one layer of the constraints stack described
+// by the SelectSettings algorithm: either the single mandatory "basic" set
+// or one of the optional, ordered "advanced" sets.
+// Reference: https://w3c.github.io/mediacapture-main/#dictdef-mediatrackconstraintset
+type MediaTrackConstraintSet = prop.MediaConstraints
+
+// MediaTrackConstraints describes the constraints used to select a driver
+// and property in GetUserMedia/GetDisplayMedia.
+// Reference: https://w3c.github.io/mediacapture-main/#dom-mediatrackconstraints
+type MediaTrackConstraints struct {
+	prop.MediaConstraints
+
+	// Advanced is an ordered list of additional constraint sets applied on
+	// top of the basic set above. Each one is tried in turn against the
+	// candidates still surviving the sets before it: if at least one
+	// candidate satisfies it, it's kept and folded into the stack;
+	// otherwise it's discarded and selection continues as if it was never
+	// specified.
+	// Reference: https://w3c.github.io/mediacapture-main/#dfn-selectsettings
+	Advanced []MediaTrackConstraintSet
+
+	// DiscardedAdvanced is filled in by selectBestDriver once selection
+	// succeeds. It lists the indices, in the order they appear in Advanced,
+	// of advanced sets that didn't survive: the set would have left no
+	// candidate satisfying the stack applied so far, so per SelectSettings
+	// it was dropped rather than failing the whole call.
+	DiscardedAdvanced []int
+
+	selectedMedia prop.Media
+	mediaSource   MediaSourceKind
+}
+
+// MediaSourceKind identifies the kind of video source a MediaTrackConstraints
+// is describing, distinguishing a physical camera from a screen or window
+// capture so that both can be requested through the same constraint type.
+type MediaSourceKind int
+
+const (
+	// Camera is the zero value, selecting a regular video input device.
+	Camera MediaSourceKind = iota
+	// Screen selects a full display capture.
+	Screen
+	// Window selects a single window capture.
+	Window
+)
+
+// SetMediaSource picks which kind of video source this constraint set
+// describes. It defaults to Camera, so existing callers that never set it
+// keep selecting regular video input devices.
+func (c *MediaTrackConstraints) SetMediaSource(kind MediaSourceKind) {
+	c.mediaSource = kind
+}
+
+// MediaStreamConstraints describes the constraints used for each track type
+// in GetUserMedia/GetDisplayMedia.
+// Reference: https://w3c.github.io/mediacapture-main/#dom-mediastreamconstraints
+type MediaStreamConstraints struct {
+	Video func(*MediaTrackConstraints)
+	Audio func(*MediaTrackConstraints)
+
+	// VideoDevices allows requesting more than one video track in a single
+	// GetUserMedia call, e.g. a camera alongside a screen share. Each entry
+	// is configured the same way as Video, and can pick its own
+	// MediaSourceKind via MediaTrackConstraints.SetMediaSource.
+	VideoDevices []func(*MediaTrackConstraints)
+}
@@ -0,0 +1,161 @@
+package mediadevices
+
+import (
+	"context"
+	"time"
+
+	"github.com/pion/mediadevices/pkg/driver"
+)
+
+// MediaDeviceType identifies whether a MediaDeviceInfo refers to a video or
+// audio input.
+type MediaDeviceType int
+
+const (
+	VideoInput MediaDeviceType = iota
+	AudioInput
+)
+
+// MediaDeviceInfo contains information that describes a single media input
+// device.
+// Reference: https://w3c.github.io/mediacapture-main/#dom-mediadeviceinfo
+type MediaDeviceInfo struct {
+	DeviceID   string
+	Kind       MediaDeviceType
+	Label      string
+	DeviceType driver.DeviceType
+}
+
+// DeviceChangeType identifies whether a DeviceChangeEvent is reporting a
+// device that appeared or one that disappeared.
+type DeviceChangeType int
+
+const (
+	DeviceAdded DeviceChangeType = iota
+	DeviceRemoved
+)
+
+// DeviceChangeEvent describes a single device that appeared or disappeared
+// since the previous EnumerateDevices snapshot.
+type DeviceChangeEvent struct {
+	Type   DeviceChangeType
+	Device MediaDeviceInfo
+}
+
+// devicePollInterval is how often WatchDevices re-runs EnumerateDevices to
+// look for hardware changes.
+const devicePollInterval = 2 * time.Second
+
+// WatchDevices watches for cameras and microphones being plugged in or
+// unplugged and reports Added/Removed events on the returned channel. The
+// channel is closed once ctx is done.
+//
+// If the active driver.Manager has a push-based driver.Notifier installed
+// (platform driver packages set one via Manager.SetNotifier when they can
+// hook native notifications such as Linux/udev, macOS/IOKit, or Windows'
+// WM_DEVICECHANGE), its events are forwarded directly. Otherwise WatchDevices
+// falls back to polling EnumerateDevices on an interval and diffing
+// snapshots.
+func WatchDevices(ctx context.Context) <-chan DeviceChangeEvent {
+	if pushed, ok := driver.GetManager().Watch(ctx); ok {
+		return watchPushed(ctx, pushed)
+	}
+	return watchPolled(ctx)
+}
+
+// watchPushed translates driver.Manager's push notifications into
+// DeviceChangeEvents.
+func watchPushed(ctx context.Context, pushed <-chan driver.Event) <-chan DeviceChangeEvent {
+	events := make(chan DeviceChangeEvent)
+
+	go func() {
+		defer close(events)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case e, ok := <-pushed:
+				if !ok {
+					return
+				}
+				info, ok := driverToDeviceInfo(e.Driver)
+				if !ok {
+					continue
+				}
+
+				changeType := DeviceAdded
+				if e.Type == driver.EventRemoved {
+					changeType = DeviceRemoved
+				}
+
+				select {
+				case events <- DeviceChangeEvent{Type: changeType, Device: info}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return events
+}
+
+// watchPolled re-runs EnumerateDevices on devicePollInterval and diffs
+// successive snapshots, for drivers that don't support push notifications.
+func watchPolled(ctx context.Context) <-chan DeviceChangeEvent {
+	events := make(chan DeviceChangeEvent)
+
+	go func() {
+		defer close(events)
+
+		ticker := time.NewTicker(devicePollInterval)
+		defer ticker.Stop()
+
+		previous := EnumerateDevices()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				current := EnumerateDevices()
+				for _, e := range diffDevices(previous, current) {
+					select {
+					case events <- e:
+					case <-ctx.Done():
+						return
+					}
+				}
+				previous = current
+			}
+		}
+	}()
+
+	return events
+}
+
+// diffDevices compares two EnumerateDevices snapshots by DeviceID and
+// reports what was added and removed between them, in that order.
+func diffDevices(previous, current []MediaDeviceInfo) []DeviceChangeEvent {
+	previousByID := make(map[string]MediaDeviceInfo, len(previous))
+	for _, d := range previous {
+		previousByID[d.DeviceID] = d
+	}
+	currentByID := make(map[string]MediaDeviceInfo, len(current))
+	for _, d := range current {
+		currentByID[d.DeviceID] = d
+	}
+
+	var events []DeviceChangeEvent
+	for _, d := range current {
+		if _, ok := previousByID[d.DeviceID]; !ok {
+			events = append(events, DeviceChangeEvent{Type: DeviceAdded, Device: d})
+		}
+	}
+	for _, d := range previous {
+		if _, ok := currentByID[d.DeviceID]; !ok {
+			events = append(events, DeviceChangeEvent{Type: DeviceRemoved, Device: d})
+		}
+	}
+	return events
+}
@@ -0,0 +1,120 @@
+package mediadevices
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+
+	"github.com/pion/mediadevices/pkg/driver"
+	"github.com/pion/mediadevices/pkg/prop"
+)
+
+// OverconstrainedError is returned when selectBestDriver can't find any
+// driver property that satisfies every requested constraint. Unlike a plain
+// "not found" error, it identifies the single constraint responsible for
+// ruling out every candidate, along with the closest property found once
+// that constraint is set aside, so that callers can retry with it relaxed.
+// Reference: https://w3c.github.io/mediacapture-main/#dom-overconstrainederror
+// Reference: https://bugzilla.mozilla.org/show_bug.cgi?id=1181896
+type OverconstrainedError struct {
+	// ConstraintName is the name of the constraint that ruled out every
+	// candidate driver property.
+	ConstraintName string
+	// Message is a human readable description of the failure.
+	Message string
+	// Candidate is the closest driver property found among all queried
+	// drivers, i.e. the one that satisfies every constraint except
+	// ConstraintName.
+	Candidate prop.Media
+}
+
+func (e *OverconstrainedError) Error() string {
+	return fmt.Sprintf("overconstrained: constraint %q is not satisfied by any candidate: %s", e.ConstraintName, e.Message)
+}
+
+func (e *OverconstrainedError) Unwrap() error {
+	return errNotFound
+}
+
+// constraintLeaf identifies a single constrainable field inside a
+// prop.MediaConstraints value, e.g. "Video.FrameRate" or "Audio.SampleRate".
+type constraintLeaf struct {
+	name  string
+	index []int
+}
+
+// constraintLeaves flattens every leaf field of a prop.MediaConstraints (and
+// its nested structs, such as the video/audio sub-constraints) into a list
+// that can each be cleared independently.
+func constraintLeaves(t reflect.Type) []constraintLeaf {
+	return appendConstraintLeaves(nil, t, nil, "")
+}
+
+func appendConstraintLeaves(leaves []constraintLeaf, t reflect.Type, index []int, name string) []constraintLeaf {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		fieldIndex := append(append([]int{}, index...), i)
+		fieldName := field.Name
+		if name != "" {
+			fieldName = name + "." + field.Name
+		}
+
+		if field.Type.Kind() == reflect.Struct {
+			leaves = appendConstraintLeaves(leaves, field.Type, fieldIndex, fieldName)
+			continue
+		}
+
+		leaves = append(leaves, constraintLeaf{name: fieldName, index: fieldIndex})
+	}
+	return leaves
+}
+
+// clear zeroes out this single leaf field on the given addressable struct
+// value, effectively removing it from consideration. v must be the
+// reflect.Value of the same type constraintLeaves walked to produce l
+// (obtained via reflect.ValueOf(ptr).Elem()).
+func (l constraintLeaf) clear(v reflect.Value) {
+	f := v.FieldByIndex(l.index)
+	f.Set(reflect.Zero(f.Type()))
+}
+
+// newOverconstrainedError figures out which single constraint is responsible
+// for rejecting every candidate driver property. It does so by recomputing
+// FitnessDistance with each constraint temporarily cleared in turn; the first
+// one whose removal unblocks a match is reported as the offending one.
+func newOverconstrainedError(constraints MediaTrackConstraints, driverProperties map[driver.Driver][]prop.Media) error {
+	base := constraints.MediaConstraints
+	for _, leaf := range constraintLeaves(reflect.TypeOf(base)) {
+		relaxed := base
+		leaf.clear(reflect.ValueOf(&relaxed).Elem())
+
+		var bestProp prop.Media
+		minFitnessDist := math.Inf(1)
+		found := false
+		for _, props := range driverProperties {
+			for _, p := range props {
+				fitnessDist, ok := relaxed.FitnessDistance(p)
+				if !ok {
+					continue
+				}
+				found = true
+				if fitnessDist < minFitnessDist {
+					minFitnessDist = fitnessDist
+					bestProp = p
+				}
+			}
+		}
+
+		if found {
+			return &OverconstrainedError{
+				ConstraintName: leaf.name,
+				Message:        fmt.Sprintf("%s is the only constraint preventing a match", leaf.name),
+				Candidate:      bestProp,
+			}
+		}
+	}
+
+	// None of the constraints individually blocked every candidate, e.g.
+	// there were no candidates at all to begin with.
+	return errNotFound
+}
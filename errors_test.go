@@ -0,0 +1,70 @@
+package mediadevices
+
+import (
+	"reflect"
+	"testing"
+)
+
+// testVideoConstraints and testAudioConstraints stand in for prop's nested
+// video/audio constraint structs so constraintLeaves/clear can be exercised
+// without depending on the prop package's concrete fields.
+type testVideoConstraints struct {
+	Width     int
+	FrameRate float32
+}
+
+type testAudioConstraints struct {
+	SampleRate int
+}
+
+type testMediaConstraints struct {
+	DeviceID string
+	Video    testVideoConstraints
+	Audio    testAudioConstraints
+}
+
+func TestConstraintLeaves(t *testing.T) {
+	leaves := constraintLeaves(reflect.TypeOf(testMediaConstraints{}))
+
+	var names []string
+	for _, l := range leaves {
+		names = append(names, l.name)
+	}
+
+	want := []string{"DeviceID", "Video.Width", "Video.FrameRate", "Audio.SampleRate"}
+	if !reflect.DeepEqual(names, want) {
+		t.Fatalf("leaf names = %v, want %v", names, want)
+	}
+}
+
+func TestConstraintLeafClear(t *testing.T) {
+	leaves := constraintLeaves(reflect.TypeOf(testMediaConstraints{}))
+
+	for _, l := range leaves {
+		c := testMediaConstraints{
+			DeviceID: "cam0",
+			Video:    testVideoConstraints{Width: 1280, FrameRate: 30},
+			Audio:    testAudioConstraints{SampleRate: 48000},
+		}
+		before := c
+
+		l.clear(reflect.ValueOf(&c).Elem())
+
+		cleared := reflect.ValueOf(c).FieldByIndex(l.index)
+		if !cleared.IsZero() {
+			t.Errorf("clear(%s): field still %v, want zero value", l.name, cleared.Interface())
+		}
+
+		// Every other leaf must be untouched.
+		for _, other := range leaves {
+			if other.name == l.name {
+				continue
+			}
+			got := reflect.ValueOf(c).FieldByIndex(other.index).Interface()
+			want := reflect.ValueOf(before).FieldByIndex(other.index).Interface()
+			if got != want {
+				t.Errorf("clear(%s) unexpectedly changed %s: got %v, want %v", l.name, other.name, got, want)
+			}
+		}
+	}
+}
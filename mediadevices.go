@@ -3,7 +3,6 @@ package mediadevices
 import (
 	"fmt"
 	"math"
-	"strings"
 
 	"github.com/pion/mediadevices/pkg/driver"
 	"github.com/pion/mediadevices/pkg/prop"
@@ -68,6 +67,18 @@ func GetUserMedia(constraints MediaStreamConstraints) (MediaStream, error) {
 		tracks = append(tracks, track)
 	}
 
+	for _, videoDevice := range constraints.VideoDevices {
+		var p MediaTrackConstraints
+		videoDevice(&p)
+		track, err := selectVideoSource(p)
+		if err != nil {
+			cleanTracks()
+			return nil, err
+		}
+
+		tracks = append(tracks, track)
+	}
+
 	if constraints.Audio != nil {
 		var p MediaTrackConstraints
 		constraints.Audio(&p)
@@ -115,56 +126,108 @@ func queryDriverProperties(filter driver.FilterFn) map[driver.Driver][]prop.Medi
 	return m
 }
 
+// driverCandidate pairs a driver with one of its properties that satisfies
+// the constraint stack built up so far.
+type driverCandidate struct {
+	driver driver.Driver
+	prop   prop.Media
+}
+
 // select implements SelectSettings algorithm.
 // Reference: https://w3c.github.io/mediacapture-main/#dfn-selectsettings
 func selectBestDriver(filter driver.FilterFn, constraints MediaTrackConstraints) (driver.Driver, MediaTrackConstraints, error) {
-	var bestDriver driver.Driver
-	var bestProp prop.Media
-	minFitnessDist := math.Inf(1)
-
 	driverProperties := queryDriverProperties(filter)
+
+	var candidates []driverCandidate
 	for d, props := range driverProperties {
-		priority := float64(d.Info().Priority)
 		for _, p := range props {
-			fitnessDist, ok := constraints.MediaConstraints.FitnessDistance(p)
-			if !ok {
-				continue
-			}
-			fitnessDist -= priority
-			if fitnessDist < minFitnessDist {
-				minFitnessDist = fitnessDist
-				bestDriver = d
-				bestProp = p
+			if _, ok := constraints.MediaConstraints.FitnessDistance(p); ok {
+				candidates = append(candidates, driverCandidate{d, p})
 			}
 		}
 	}
 
-	if bestDriver == nil {
-		var foundProperties []string
-		for _, props := range driverProperties {
-			for _, p := range props {
-				foundProperties = append(foundProperties, fmt.Sprint(&p))
-			}
-		}
-
-		err := fmt.Errorf(`%w:
-============ Found Properties ============
-
-%s
+	if len(candidates) == 0 {
+		return nil, MediaTrackConstraints{}, newOverconstrainedError(constraints, driverProperties)
+	}
 
-=============== Constraints ==============
+	// Apply each advanced set greedily, on top of the basic set: keep it
+	// folded into the stack only if at least one candidate still satisfies
+	// everything applied so far, otherwise discard it and move on to the
+	// next one. Because candidates is guaranteed non-empty going in and a
+	// set that would empty it is always discarded instead of applied, this
+	// can never fail outright; it can only narrow down which candidates and
+	// sets survive.
+	survivors, appliedAdvanced, discarded := applyAdvancedSets(len(constraints.Advanced), len(candidates), func(advancedIdx, candidateIdx int) bool {
+		_, ok := constraints.Advanced[advancedIdx].FitnessDistance(candidates[candidateIdx].prop)
+		return ok
+	})
+
+	applied := []MediaTrackConstraintSet{constraints.MediaConstraints}
+	for _, i := range appliedAdvanced {
+		applied = append(applied, constraints.Advanced[i])
+	}
 
-%s
-`, errNotFound, strings.Join(foundProperties, "\n\n"), &constraints)
-		return nil, MediaTrackConstraints{}, err
+	var bestDriver driver.Driver
+	var bestProp prop.Media
+	minFitnessDist := math.Inf(1)
+	for _, ci := range survivors {
+		c := candidates[ci]
+		priority := float64(c.driver.Info().Priority)
+		var fitnessDist float64
+		for _, set := range applied {
+			d, _ := set.FitnessDistance(c.prop)
+			fitnessDist += d
+		}
+		fitnessDist -= priority
+		if fitnessDist < minFitnessDist {
+			minFitnessDist = fitnessDist
+			bestDriver = c.driver
+			bestProp = c.prop
+		}
 	}
 
 	constraints.selectedMedia = prop.Media{}
-	constraints.selectedMedia.MergeConstraints(constraints.MediaConstraints)
+	for _, set := range applied {
+		constraints.selectedMedia.MergeConstraints(set)
+	}
 	constraints.selectedMedia.Merge(bestProp)
+	constraints.DiscardedAdvanced = discarded
 	return bestDriver, constraints, nil
 }
 
+// applyAdvancedSets implements the greedy portion of SelectSettings for the
+// Advanced constraint-set stack. candidateCount candidates start out
+// surviving; for each of the nAdvanced sets in order, fits(i, j) reports
+// whether candidate j still matches set i. A set is folded into applied
+// (and survivors narrowed to only the candidates it matches) if it matches
+// at least one surviving candidate, otherwise its index is recorded in
+// discarded and survivors is left untouched. survivors is therefore never
+// empty on return as long as candidateCount > 0 going in.
+func applyAdvancedSets(nAdvanced, candidateCount int, fits func(advancedIdx, candidateIdx int) bool) (survivors, applied, discarded []int) {
+	survivors = make([]int, candidateCount)
+	for i := range survivors {
+		survivors[i] = i
+	}
+
+	for i := 0; i < nAdvanced; i++ {
+		var filtered []int
+		for _, j := range survivors {
+			if fits(i, j) {
+				filtered = append(filtered, j)
+			}
+		}
+		if len(filtered) == 0 {
+			discarded = append(discarded, i)
+			continue
+		}
+		survivors = filtered
+		applied = append(applied, i)
+	}
+
+	return survivors, applied, discarded
+}
+
 func selectAudio(constraints MediaTrackConstraints) (Track, error) {
 	typeFilter := driver.FilterAudioRecorder()
 
@@ -176,10 +239,25 @@ func selectAudio(constraints MediaTrackConstraints) (Track, error) {
 	return newAudioTrack(d, c)
 }
 
-func selectVideo(constraints MediaTrackConstraints) (Track, error) {
+// selectVideoSource dispatches to the driver filter matching the
+// constraints' MediaSourceKind, consolidating what used to be separate
+// camera and screen selection paths into one.
+func selectVideoSource(constraints MediaTrackConstraints) (Track, error) {
 	typeFilter := driver.FilterVideoRecorder()
-	notScreenFilter := driver.FilterNot(driver.FilterDeviceType(driver.Screen))
-	filter := driver.FilterAnd(typeFilter, notScreenFilter)
+
+	var sourceFilter driver.FilterFn
+	switch constraints.mediaSource {
+	case Screen:
+		sourceFilter = driver.FilterDeviceType(driver.Screen)
+	case Window:
+		sourceFilter = driver.FilterDeviceType(driver.Window)
+	default:
+		sourceFilter = driver.FilterAnd(
+			driver.FilterNot(driver.FilterDeviceType(driver.Screen)),
+			driver.FilterNot(driver.FilterDeviceType(driver.Window)),
+		)
+	}
+	filter := driver.FilterAnd(typeFilter, sourceFilter)
 
 	d, c, err := selectBestDriver(filter, constraints)
 	if err != nil {
@@ -189,17 +267,20 @@ func selectVideo(constraints MediaTrackConstraints) (Track, error) {
 	return newVideoTrack(d, c)
 }
 
-func selectScreen(constraints MediaTrackConstraints) (Track, error) {
-	typeFilter := driver.FilterVideoRecorder()
-	screenFilter := driver.FilterDeviceType(driver.Screen)
-	filter := driver.FilterAnd(typeFilter, screenFilter)
+// selectVideo selects the video track for the single-Video callback in
+// MediaStreamConstraints: a camera unless the callback picked another
+// MediaSourceKind. Kept as a thin wrapper around selectVideoSource for
+// backward compatibility.
+func selectVideo(constraints MediaTrackConstraints) (Track, error) {
+	return selectVideoSource(constraints)
+}
 
-	d, c, err := selectBestDriver(filter, constraints)
-	if err != nil {
-		return nil, err
+// selectScreen selects a screen/window capture video track.
+func selectScreen(constraints MediaTrackConstraints) (Track, error) {
+	if constraints.mediaSource == Camera {
+		constraints.SetMediaSource(Screen)
 	}
-
-	return newVideoTrack(d, c)
+	return selectVideoSource(constraints)
 }
 
 func EnumerateDevices() []MediaDeviceInfo {
@@ -207,22 +288,32 @@ func EnumerateDevices() []MediaDeviceInfo {
 		driver.FilterFn(func(driver.Driver) bool { return true }))
 	info := make([]MediaDeviceInfo, 0, len(drivers))
 	for _, d := range drivers {
-		var kind MediaDeviceType
-		switch {
-		case driver.FilterVideoRecorder()(d):
-			kind = VideoInput
-		case driver.FilterAudioRecorder()(d):
-			kind = AudioInput
-		default:
-			continue
+		if i, ok := driverToDeviceInfo(d); ok {
+			info = append(info, i)
 		}
-		driverInfo := d.Info()
-		info = append(info, MediaDeviceInfo{
-			DeviceID:   d.ID(),
-			Kind:       kind,
-			Label:      driverInfo.Label,
-			DeviceType: driverInfo.DeviceType,
-		})
 	}
 	return info
 }
+
+// driverToDeviceInfo converts a driver.Driver into the MediaDeviceInfo shape
+// EnumerateDevices and WatchDevices both report, or ok=false if d is neither
+// a video nor an audio input.
+func driverToDeviceInfo(d driver.Driver) (info MediaDeviceInfo, ok bool) {
+	var kind MediaDeviceType
+	switch {
+	case driver.FilterVideoRecorder()(d):
+		kind = VideoInput
+	case driver.FilterAudioRecorder()(d):
+		kind = AudioInput
+	default:
+		return MediaDeviceInfo{}, false
+	}
+
+	driverInfo := d.Info()
+	return MediaDeviceInfo{
+		DeviceID:   d.ID(),
+		Kind:       kind,
+		Label:      driverInfo.Label,
+		DeviceType: driverInfo.DeviceType,
+	}, true
+}
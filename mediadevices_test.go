@@ -0,0 +1,110 @@
+package mediadevices
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestApplyAdvancedSets(t *testing.T) {
+	cases := []struct {
+		name           string
+		nAdvanced      int
+		candidateCount int
+		// fits[i][j] reports whether candidate j matches advanced set i.
+		fits             [][]bool
+		wantSurvivors    []int
+		wantApplied      []int
+		wantDiscarded    []int
+		wantSurvivorsLen int
+	}{
+		{
+			name:           "no advanced sets keeps every candidate",
+			nAdvanced:      0,
+			candidateCount: 3,
+			fits:           nil,
+			wantSurvivors:  []int{0, 1, 2},
+			wantApplied:    nil,
+			wantDiscarded:  nil,
+		},
+		{
+			name:           "a set matching every candidate is kept without narrowing",
+			nAdvanced:      1,
+			candidateCount: 2,
+			fits: [][]bool{
+				{true, true},
+			},
+			wantSurvivors: []int{0, 1},
+			wantApplied:   []int{0},
+			wantDiscarded: nil,
+		},
+		{
+			name:           "a set matching some candidates narrows survivors and is kept",
+			nAdvanced:      1,
+			candidateCount: 3,
+			fits: [][]bool{
+				{false, true, true},
+			},
+			wantSurvivors: []int{1, 2},
+			wantApplied:   []int{0},
+			wantDiscarded: nil,
+		},
+		{
+			name:           "a set matching no candidate is discarded and survivors are untouched",
+			nAdvanced:      1,
+			candidateCount: 2,
+			fits: [][]bool{
+				{false, false},
+			},
+			wantSurvivors: []int{0, 1},
+			wantApplied:   nil,
+			wantDiscarded: []int{0},
+		},
+		{
+			name:           "later sets are evaluated against the narrowed survivor list",
+			nAdvanced:      2,
+			candidateCount: 3,
+			fits: [][]bool{
+				{true, true, false}, // set 0: keeps candidates 0,1
+				{false, true, true}, // set 1: fits 1 and 2, but 2 was already ruled out by set 0
+			},
+			wantSurvivors: []int{1},
+			wantApplied:   []int{0, 1},
+			wantDiscarded: nil,
+		},
+		{
+			name:           "a set that would empty the survivor list is discarded, not applied",
+			nAdvanced:      2,
+			candidateCount: 2,
+			fits: [][]bool{
+				{true, false},  // set 0: keeps candidate 0
+				{false, false}, // set 1: matches nothing left
+			},
+			wantSurvivors: []int{0},
+			wantApplied:   []int{0},
+			wantDiscarded: []int{1},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			survivors, applied, discarded := applyAdvancedSets(tc.nAdvanced, tc.candidateCount, func(i, j int) bool {
+				return tc.fits[i][j]
+			})
+
+			sort.Ints(survivors)
+			if !reflect.DeepEqual(survivors, tc.wantSurvivors) {
+				t.Errorf("survivors = %v, want %v", survivors, tc.wantSurvivors)
+			}
+			if !reflect.DeepEqual(applied, tc.wantApplied) {
+				t.Errorf("applied = %v, want %v", applied, tc.wantApplied)
+			}
+			if !reflect.DeepEqual(discarded, tc.wantDiscarded) {
+				t.Errorf("discarded = %v, want %v", discarded, tc.wantDiscarded)
+			}
+			if len(survivors) == 0 && tc.candidateCount > 0 {
+				t.Errorf("survivors must never be empty when candidateCount > 0")
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+// Package driver enumerates and selects the platform video/audio input
+// sources mediadevices builds tracks from.
+package driver
+
+import (
+	"github.com/pion/mediadevices/pkg/io/audio"
+	"github.com/pion/mediadevices/pkg/io/video"
+	"github.com/pion/mediadevices/pkg/prop"
+)
+
+// State represents where a Driver sits in its open/close lifecycle.
+type State int
+
+const (
+	StateClosed State = iota
+	StateOpened
+)
+
+// DeviceType identifies the kind of device a Driver represents.
+type DeviceType string
+
+const (
+	Camera     DeviceType = "Camera"
+	Microphone DeviceType = "Microphone"
+	Screen     DeviceType = "Screen"
+	Window     DeviceType = "Window"
+)
+
+// Priority influences which Driver selectBestDriver prefers when multiple
+// candidates otherwise tie on FitnessDistance; higher is preferred.
+type Priority float64
+
+// Info describes the static, non-capability information about a Driver.
+type Info struct {
+	Label      string
+	DeviceType DeviceType
+	Priority   Priority
+}
+
+// Driver is implemented by every video/audio input source this package can
+// enumerate and select from.
+type Driver interface {
+	Open() error
+	Close() error
+	ID() string
+	Info() Info
+	Status() State
+	Properties() []prop.Media
+}
+
+// VideoRecorder is implemented by Drivers that can produce a stream of video
+// frames at a given property.
+type VideoRecorder interface {
+	Driver
+	VideoRecord(prop.Media) (video.Reader, error)
+}
+
+// AudioRecorder is implemented by Drivers that can produce a stream of audio
+// samples at a given property.
+type AudioRecorder interface {
+	Driver
+	AudioRecord(prop.Media) (audio.Reader, error)
+}
+
+// FilterFn reports whether a Driver matches some selection criteria.
+type FilterFn func(Driver) bool
+
+// FilterAnd matches a Driver that every one of filters matches.
+func FilterAnd(filters ...FilterFn) FilterFn {
+	return func(d Driver) bool {
+		for _, f := range filters {
+			if !f(d) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// FilterNot matches a Driver that filter doesn't match.
+func FilterNot(filter FilterFn) FilterFn {
+	return func(d Driver) bool {
+		return !filter(d)
+	}
+}
+
+// FilterDeviceType matches a Driver whose Info().DeviceType is want.
+func FilterDeviceType(want DeviceType) FilterFn {
+	return func(d Driver) bool {
+		return d.Info().DeviceType == want
+	}
+}
+
+// FilterVideoRecorder matches a Driver that implements VideoRecorder.
+func FilterVideoRecorder() FilterFn {
+	return func(d Driver) bool {
+		_, ok := d.(VideoRecorder)
+		return ok
+	}
+}
+
+// FilterAudioRecorder matches a Driver that implements AudioRecorder.
+func FilterAudioRecorder() FilterFn {
+	return func(d Driver) bool {
+		_, ok := d.(AudioRecorder)
+		return ok
+	}
+}
@@ -0,0 +1,94 @@
+package driver
+
+import (
+	"context"
+	"sync"
+)
+
+// EventType identifies whether a device-change Event is an addition or a
+// removal.
+type EventType int
+
+const (
+	EventAdded EventType = iota
+	EventRemoved
+)
+
+// Event reports a single Driver being registered or unregistered.
+type Event struct {
+	Type   EventType
+	Driver Driver
+}
+
+// Notifier is implemented by platform driver packages that can push
+// notifications when devices are plugged in or unplugged (Linux/udev,
+// macOS/IOKit, Windows/WM_DEVICECHANGE) instead of requiring callers to poll
+// Query on an interval.
+type Notifier interface {
+	// Notify streams device add/remove events until ctx is done, at which
+	// point the returned channel is closed.
+	Notify(ctx context.Context) <-chan Event
+}
+
+// Manager keeps track of every registered Driver and lets callers query
+// drivers matching a filter.
+type Manager struct {
+	mu       sync.Mutex
+	drivers  []Driver
+	notifier Notifier
+}
+
+var manager = &Manager{}
+
+// GetManager returns the process-wide Manager that platform driver packages
+// register themselves with.
+func GetManager() *Manager {
+	return manager
+}
+
+// Register adds d to the set of drivers Query can return. Platform driver
+// packages call this from their init functions.
+func (m *Manager) Register(d Driver) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.drivers = append(m.drivers, d)
+}
+
+// Query returns every registered Driver that filter matches.
+func (m *Manager) Query(filter FilterFn) []Driver {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var matched []Driver
+	for _, d := range m.drivers {
+		if filter(d) {
+			matched = append(matched, d)
+		}
+	}
+	return matched
+}
+
+// SetNotifier installs a push-based Notifier, so that Watch can report its
+// events and callers don't need to poll Query.
+// Platform driver packages call this from their init/registration code when
+// the platform supports native device-change notifications.
+func (m *Manager) SetNotifier(n Notifier) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.notifier = n
+}
+
+// Watch returns a channel of device add/remove events sourced from the
+// installed Notifier, and true, if one has been set via SetNotifier.
+// Otherwise it returns (nil, false) and callers are expected to fall back to
+// polling Query themselves.
+func (m *Manager) Watch(ctx context.Context) (<-chan Event, bool) {
+	m.mu.Lock()
+	n := m.notifier
+	m.mu.Unlock()
+
+	if n == nil {
+		return nil, false
+	}
+	return n.Notify(ctx), true
+}
@@ -0,0 +1,120 @@
+// Package prop describes the capture properties drivers can produce and the
+// constraints used to select among them.
+package prop
+
+// Video describes the video-specific fields of a capture property.
+type Video struct {
+	Width, Height int
+	FrameRate     float32
+}
+
+// Audio describes the audio-specific fields of a capture property.
+type Audio struct {
+	SampleRate   int
+	ChannelCount int
+}
+
+// Media describes one concrete, fully-specified capture property, such as
+// what a driver.Driver.Properties() entry offers or a track currently has
+// selected.
+type Media struct {
+	DeviceID string
+	Video
+	Audio
+}
+
+// MergeConstraints copies every set field of c into m, giving callers a
+// starting point built from what was requested before the actual selected
+// Media is merged in on top via Merge.
+func (m *Media) MergeConstraints(c MediaConstraints) {
+	if c.DeviceID != nil {
+		m.DeviceID = *c.DeviceID
+	}
+	if c.Video.Width != nil {
+		m.Width = *c.Video.Width
+	}
+	if c.Video.Height != nil {
+		m.Height = *c.Video.Height
+	}
+	if c.Video.FrameRate != nil {
+		m.FrameRate = *c.Video.FrameRate
+	}
+	if c.Audio.SampleRate != nil {
+		m.SampleRate = *c.Audio.SampleRate
+	}
+	if c.Audio.ChannelCount != nil {
+		m.ChannelCount = *c.Audio.ChannelCount
+	}
+}
+
+// Merge copies every non-zero field of other into m.
+func (m *Media) Merge(other Media) {
+	if other.DeviceID != "" {
+		m.DeviceID = other.DeviceID
+	}
+	if other.Width != 0 {
+		m.Width = other.Width
+	}
+	if other.Height != 0 {
+		m.Height = other.Height
+	}
+	if other.FrameRate != 0 {
+		m.FrameRate = other.FrameRate
+	}
+	if other.SampleRate != 0 {
+		m.SampleRate = other.SampleRate
+	}
+	if other.ChannelCount != 0 {
+		m.ChannelCount = other.ChannelCount
+	}
+}
+
+// VideoConstraints constrains a subset of Video's fields. A nil field is
+// unconstrained and matches any value.
+type VideoConstraints struct {
+	Width, Height *int
+	FrameRate     *float32
+}
+
+// AudioConstraints constrains a subset of Audio's fields. A nil field is
+// unconstrained and matches any value.
+type AudioConstraints struct {
+	SampleRate   *int
+	ChannelCount *int
+}
+
+// MediaConstraints describes one layer of the constraint stack used by
+// SelectSettings (see mediadevices.selectBestDriver): a DeviceID plus the
+// video/audio fields a candidate Media must match exactly. A nil field is
+// unconstrained and matches anything.
+type MediaConstraints struct {
+	DeviceID *string
+	Video    VideoConstraints
+	Audio    AudioConstraints
+}
+
+// FitnessDistance reports how well media satisfies c. ok is false if any set
+// field doesn't match media exactly. Every constraint here is
+// exact-or-unconstrained rather than a range with an ideal to minimize
+// toward, so the returned distance is always 0 when ok is true.
+func (c MediaConstraints) FitnessDistance(media Media) (float64, bool) {
+	ok := stringSatisfies(c.DeviceID, media.DeviceID) &&
+		intSatisfies(c.Video.Width, media.Width) &&
+		intSatisfies(c.Video.Height, media.Height) &&
+		float32Satisfies(c.Video.FrameRate, media.FrameRate) &&
+		intSatisfies(c.Audio.SampleRate, media.SampleRate) &&
+		intSatisfies(c.Audio.ChannelCount, media.ChannelCount)
+	return 0, ok
+}
+
+func intSatisfies(c *int, v int) bool {
+	return c == nil || *c == v
+}
+
+func float32Satisfies(c *float32, v float32) bool {
+	return c == nil || *c == v
+}
+
+func stringSatisfies(c *string, v string) bool {
+	return c == nil || *c == v
+}
@@ -0,0 +1,210 @@
+package mediadevices
+
+import (
+	"math"
+	"sync"
+
+	"github.com/pion/mediadevices/pkg/driver"
+	"github.com/pion/mediadevices/pkg/io/audio"
+	"github.com/pion/mediadevices/pkg/io/video"
+	"github.com/pion/mediadevices/pkg/prop"
+)
+
+// Track is the common interface implemented by both audio and video tracks
+// produced by GetUserMedia/GetDisplayMedia.
+// Reference: https://w3c.github.io/mediacapture-main/#mediastreamtrack
+type Track interface {
+	// GetCapabilities returns every property the underlying driver is able
+	// to produce, regardless of which one is currently selected.
+	// Reference: https://w3c.github.io/mediacapture-main/#dom-mediastreamtrack-getcapabilities
+	GetCapabilities() []prop.Media
+	// GetSettings returns the property currently in use by the track.
+	// Reference: https://w3c.github.io/mediacapture-main/#dom-mediastreamtrack-getsettings
+	GetSettings() prop.Media
+	// ApplyConstraints re-runs constraint selection against the properties
+	// already offered by the driver backing this track and, if one fits,
+	// reconfigures the track to use it without switching drivers. If no
+	// property on the current driver fits, it returns an
+	// *OverconstrainedError rather than silently switching to a different
+	// driver.
+	// Reference: https://w3c.github.io/mediacapture-main/#dom-mediastreamtrack-applyconstraints
+	ApplyConstraints(constraints MediaTrackConstraints) error
+	Stop()
+}
+
+// reopener is implemented by videoTrack and audioTrack: it asks the
+// already-open driver for a new stream at a newly selected property and
+// replaces the track's reader with it. The driver is not closed or reopened,
+// and the previous reader is not stopped.
+type reopener interface {
+	reopen(prop.Media) error
+}
+
+// baseTrack holds the state and behavior shared by videoTrack and
+// audioTrack: the driver backing the track and the constraints/property
+// currently in effect.
+type baseTrack struct {
+	mu          sync.Mutex
+	d           driver.Driver
+	constraints MediaTrackConstraints
+}
+
+func newBaseTrack(d driver.Driver, constraints MediaTrackConstraints) baseTrack {
+	return baseTrack{d: d, constraints: constraints}
+}
+
+func (t *baseTrack) GetCapabilities() []prop.Media {
+	return t.d.Properties()
+}
+
+func (t *baseTrack) GetSettings() prop.Media {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.constraints.selectedMedia
+}
+
+// applyConstraints is shared by videoTrack.ApplyConstraints and
+// audioTrack.ApplyConstraints. It runs the same basic-set-then-greedy-
+// advanced-stack selection selectBestDriver uses, but against only the
+// properties the current driver already offers, so a call can never switch
+// drivers. If a property fits, r.reopen is asked to start reading at that
+// property before GetSettings() is updated.
+func (t *baseTrack) applyConstraints(constraints MediaTrackConstraints, r reopener) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	props := t.d.Properties()
+
+	var candidates []prop.Media
+	for _, p := range props {
+		if _, ok := constraints.MediaConstraints.FitnessDistance(p); ok {
+			candidates = append(candidates, p)
+		}
+	}
+
+	if len(candidates) == 0 {
+		return newOverconstrainedError(constraints, map[driver.Driver][]prop.Media{t.d: props})
+	}
+
+	survivors, appliedAdvanced, discarded := applyAdvancedSets(len(constraints.Advanced), len(candidates), func(advancedIdx, candidateIdx int) bool {
+		_, ok := constraints.Advanced[advancedIdx].FitnessDistance(candidates[candidateIdx])
+		return ok
+	})
+
+	applied := []MediaTrackConstraintSet{constraints.MediaConstraints}
+	for _, i := range appliedAdvanced {
+		applied = append(applied, constraints.Advanced[i])
+	}
+
+	var bestProp prop.Media
+	minFitnessDist := math.Inf(1)
+	for _, ci := range survivors {
+		p := candidates[ci]
+		var fitnessDist float64
+		for _, set := range applied {
+			d, _ := set.FitnessDistance(p)
+			fitnessDist += d
+		}
+		if fitnessDist < minFitnessDist {
+			minFitnessDist = fitnessDist
+			bestProp = p
+		}
+	}
+
+	if err := r.reopen(bestProp); err != nil {
+		return err
+	}
+
+	constraints.selectedMedia = prop.Media{}
+	for _, set := range applied {
+		constraints.selectedMedia.MergeConstraints(set)
+	}
+	constraints.selectedMedia.Merge(bestProp)
+	constraints.DiscardedAdvanced = discarded
+	t.constraints = constraints
+	return nil
+}
+
+type videoTrack struct {
+	baseTrack
+	reader video.Reader
+}
+
+func newVideoTrack(d driver.Driver, constraints MediaTrackConstraints) (Track, error) {
+	if d.Status() == driver.StateClosed {
+		if err := d.Open(); err != nil {
+			return nil, err
+		}
+	}
+
+	r, err := d.(driver.VideoRecorder).VideoRecord(constraints.selectedMedia)
+	if err != nil {
+		return nil, err
+	}
+
+	return &videoTrack{baseTrack: newBaseTrack(d, constraints), reader: r}, nil
+}
+
+func (t *videoTrack) ApplyConstraints(constraints MediaTrackConstraints) error {
+	return t.applyConstraints(constraints, t)
+}
+
+// reopen starts a new video stream at p so the encoder chain reading from
+// t.reader picks up the new frame size/rate on its very next Read; the
+// previous stream is dropped in favor of the new one.
+func (t *videoTrack) reopen(p prop.Media) error {
+	r, err := t.d.(driver.VideoRecorder).VideoRecord(p)
+	if err != nil {
+		return err
+	}
+	t.reader = r
+	return nil
+}
+
+func (t *videoTrack) Stop() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.d.Close()
+}
+
+type audioTrack struct {
+	baseTrack
+	reader audio.Reader
+}
+
+func newAudioTrack(d driver.Driver, constraints MediaTrackConstraints) (Track, error) {
+	if d.Status() == driver.StateClosed {
+		if err := d.Open(); err != nil {
+			return nil, err
+		}
+	}
+
+	r, err := d.(driver.AudioRecorder).AudioRecord(constraints.selectedMedia)
+	if err != nil {
+		return nil, err
+	}
+
+	return &audioTrack{baseTrack: newBaseTrack(d, constraints), reader: r}, nil
+}
+
+func (t *audioTrack) ApplyConstraints(constraints MediaTrackConstraints) error {
+	return t.applyConstraints(constraints, t)
+}
+
+// reopen starts a new audio stream at p so the encoder chain reading from
+// t.reader picks up the new sample rate/format on its very next Read; the
+// previous stream is dropped in favor of the new one.
+func (t *audioTrack) reopen(p prop.Media) error {
+	r, err := t.d.(driver.AudioRecorder).AudioRecord(p)
+	if err != nil {
+		return err
+	}
+	t.reader = r
+	return nil
+}
+
+func (t *audioTrack) Stop() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.d.Close()
+}
@@ -0,0 +1,138 @@
+package mediadevices
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/pion/mediadevices/pkg/driver"
+	"github.com/pion/mediadevices/pkg/prop"
+)
+
+// fakeDriver stands in for a driver.Driver backing a track under test,
+// offering a fixed set of properties without needing a real platform driver.
+type fakeDriver struct {
+	props []prop.Media
+}
+
+func (d *fakeDriver) Open() error              { return nil }
+func (d *fakeDriver) Close() error             { return nil }
+func (d *fakeDriver) ID() string               { return "fake" }
+func (d *fakeDriver) Info() driver.Info        { return driver.Info{} }
+func (d *fakeDriver) Status() driver.State     { return driver.StateOpened }
+func (d *fakeDriver) Properties() []prop.Media { return d.props }
+
+// fakeTrack stands in for videoTrack/audioTrack: a baseTrack plus a reader
+// field that reopen only ever touches once it has actually succeeded, so
+// tests can tell whether applyConstraints left state partially mutated.
+type fakeTrack struct {
+	baseTrack
+	reader    string
+	reopenErr error
+	reopens   int
+}
+
+func (t *fakeTrack) reopen(p prop.Media) error {
+	t.reopens++
+	if t.reopenErr != nil {
+		return t.reopenErr
+	}
+	t.reader = p.DeviceID
+	return nil
+}
+
+func strPtr(s string) *string { return &s }
+func intPtr(i int) *int       { return &i }
+
+func TestBaseTrackApplyConstraintsPicksFittingProperty(t *testing.T) {
+	d := &fakeDriver{props: []prop.Media{
+		{DeviceID: "cam-wide", Video: prop.Video{Width: 640, Height: 480}},
+		{DeviceID: "cam-narrow", Video: prop.Video{Width: 1280, Height: 720}},
+	}}
+	track := &fakeTrack{baseTrack: newBaseTrack(d, MediaTrackConstraints{})}
+
+	constraints := MediaTrackConstraints{
+		MediaConstraints: prop.MediaConstraints{DeviceID: strPtr("cam-narrow")},
+	}
+
+	if err := track.applyConstraints(constraints, track); err != nil {
+		t.Fatalf("applyConstraints() = %v, want nil", err)
+	}
+
+	if track.reopens != 1 {
+		t.Errorf("reopen called %d times, want exactly 1", track.reopens)
+	}
+	if track.reader != "cam-narrow" {
+		t.Errorf("reopened with DeviceID %q, want %q", track.reader, "cam-narrow")
+	}
+	if got := track.constraints.selectedMedia.DeviceID; got != "cam-narrow" {
+		t.Errorf("selectedMedia.DeviceID = %q, want %q", got, "cam-narrow")
+	}
+}
+
+// TestBaseTrackApplyConstraintsAdvancedNarrowing checks that
+// baseTrack.applyConstraints narrows/discards Advanced sets exactly the way
+// selectBestDriver does, since both now share applyAdvancedSets.
+func TestBaseTrackApplyConstraintsAdvancedNarrowing(t *testing.T) {
+	d := &fakeDriver{props: []prop.Media{
+		{DeviceID: "a", Video: prop.Video{Width: 640}},
+		{DeviceID: "b", Video: prop.Video{Width: 1280}},
+		{DeviceID: "c", Video: prop.Video{Width: 1920}},
+	}}
+	track := &fakeTrack{baseTrack: newBaseTrack(d, MediaTrackConstraints{})}
+
+	constraints := MediaTrackConstraints{
+		Advanced: []MediaTrackConstraintSet{
+			{Video: prop.VideoConstraints{Width: intPtr(1280)}}, // set 0: keeps "b" only
+			{DeviceID: strPtr("nonexistent")},                   // set 1: matches nothing left over
+		},
+	}
+
+	if err := track.applyConstraints(constraints, track); err != nil {
+		t.Fatalf("applyConstraints() = %v, want nil", err)
+	}
+
+	if !reflect.DeepEqual(track.constraints.DiscardedAdvanced, []int{1}) {
+		t.Errorf("DiscardedAdvanced = %v, want [1]", track.constraints.DiscardedAdvanced)
+	}
+	if track.reader != "b" {
+		t.Errorf("reopened with DeviceID %q, want %q (the only candidate surviving set 0)", track.reader, "b")
+	}
+}
+
+// TestBaseTrackApplyConstraintsReopenFailureLeavesStateUnchanged verifies
+// that t.constraints (and the reader reopen would otherwise replace) is only
+// ever written after r.reopen succeeds, rather than assuming it from reading
+// applyConstraints.
+func TestBaseTrackApplyConstraintsReopenFailureLeavesStateUnchanged(t *testing.T) {
+	d := &fakeDriver{props: []prop.Media{
+		{DeviceID: "cam0", Video: prop.Video{Width: 640}},
+	}}
+
+	original := MediaTrackConstraints{}
+	original.selectedMedia = prop.Media{DeviceID: "previous"}
+
+	track := &fakeTrack{
+		baseTrack: newBaseTrack(d, original),
+		reader:    "previous-reader",
+		reopenErr: errors.New("reopen failed"),
+	}
+
+	constraints := MediaTrackConstraints{
+		MediaConstraints: prop.MediaConstraints{DeviceID: strPtr("cam0")},
+	}
+
+	if err := track.applyConstraints(constraints, track); err == nil {
+		t.Fatal("applyConstraints() = nil, want error")
+	}
+
+	if track.reopens != 1 {
+		t.Errorf("reopen called %d times, want exactly 1", track.reopens)
+	}
+	if track.reader != "previous-reader" {
+		t.Errorf("reader = %q, want unchanged %q", track.reader, "previous-reader")
+	}
+	if !reflect.DeepEqual(track.constraints, original) {
+		t.Errorf("constraints = %+v, want unchanged %+v", track.constraints, original)
+	}
+}